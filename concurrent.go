@@ -0,0 +1,240 @@
+package skiplist
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// DefaultShardCount is the number of shards ConcurrentSkipList uses when
+// none is given explicitly.
+const DefaultShardCount = 32
+
+// ConcurrentSkipList is a sharded wrapper around N independent SkipList
+// instances. Keys are routed to a shard by hash modulo len(shards), so
+// writers touching different shards never contend on the same mutex.
+// Global-order operations (Front, Range, Iter) merge the shards back
+// together with a small heap.
+type ConcurrentSkipList[T Sortable] struct {
+	shards []*SkipList[T]
+}
+
+// NewConcurrent creates a ConcurrentSkipList with DefaultShardCount shards.
+func NewConcurrent[T Sortable]() *ConcurrentSkipList[T] {
+	return NewConcurrentWithShards[T](DefaultShardCount)
+}
+
+// NewConcurrentWithShards creates a ConcurrentSkipList with the given number
+// of shards, each backed by its own SkipList and RWMutex.
+func NewConcurrentWithShards[T Sortable](shardCount int) *ConcurrentSkipList[T] {
+	if shardCount < 1 {
+		panic("shardCount for a ConcurrentSkipList must be a positive integer")
+	}
+
+	shards := make([]*SkipList[T], shardCount)
+	for i := range shards {
+		shards[i] = New[T]()
+	}
+
+	return &ConcurrentSkipList[T]{shards: shards}
+}
+
+// shardFor returns the shard responsible for key.
+func (list *ConcurrentSkipList[T]) shardFor(key T) *SkipList[T] {
+	return list.shards[hashKey(key)%uint64(len(list.shards))]
+}
+
+// Len returns the total number of elements across all shards.
+func (list *ConcurrentSkipList[T]) Len() int {
+	total := 0
+	for _, s := range list.shards {
+		total += s.Len()
+	}
+	return total
+}
+
+// Get finds an element by key. It returns the element pointer if found, nil
+// if not found.
+func (list *ConcurrentSkipList[T]) Get(key T) *Element[T] {
+	return list.shardFor(key).Get(key)
+}
+
+// Set inserts a value in the list with the specified key, ordered by the
+// key. If the key exists, it updates the value in the existing node.
+func (list *ConcurrentSkipList[T]) Set(key T, value interface{}) *Element[T] {
+	return list.shardFor(key).Set(key, value)
+}
+
+// Remove deletes an element from the list. Returns the removed element
+// pointer if found, nil if not found.
+func (list *ConcurrentSkipList[T]) Remove(key T) *Element[T] {
+	return list.shardFor(key).Remove(key)
+}
+
+// Front returns the element with the smallest key across all shards, or nil
+// if the list is empty.
+func (list *ConcurrentSkipList[T]) Front() *Element[T] {
+	var min *Element[T]
+	for _, s := range list.shards {
+		e := s.Front()
+		if e == nil {
+			continue
+		}
+		if min == nil || e.Key() < min.Key() {
+			min = e
+		}
+	}
+	return min
+}
+
+// Range returns, in key order, every element across all shards whose key
+// falls within [lo, hi].
+func (list *ConcurrentSkipList[T]) Range(lo, hi T) []*Element[T] {
+	slices := make([][]*Element[T], len(list.shards))
+	for i, s := range list.shards {
+		slices[i] = s.Range(lo, hi)
+	}
+	return mergeByKey(slices)
+}
+
+// Iter returns every element across all shards in ascending key order.
+func (list *ConcurrentSkipList[T]) Iter() []*Element[T] {
+	slices := make([][]*Element[T], len(list.shards))
+	for i, s := range list.shards {
+		slices[i] = shardElements(s)
+	}
+	return mergeByKey(slices)
+}
+
+// shardElements walks a single shard's level-0 chain into a slice.
+func shardElements[T Sortable](s *SkipList[T]) []*Element[T] {
+	var elems []*Element[T]
+	for e := s.Front(); e != nil; e = e.Next() {
+		elems = append(elems, e)
+	}
+	return elems
+}
+
+// sliceCursor tracks the current position of one shard's already-sorted
+// slice while it is merged with the others.
+type sliceCursor[T Sortable] struct {
+	elems []*Element[T]
+	pos   int
+}
+
+func (c *sliceCursor[T]) peek() *Element[T] { return c.elems[c.pos] }
+
+// cursorHeap is a min-heap of sliceCursors ordered by the key each one is
+// currently pointing at.
+type cursorHeap[T Sortable] []*sliceCursor[T]
+
+func (h cursorHeap[T]) Len() int           { return len(h) }
+func (h cursorHeap[T]) Less(i, j int) bool { return h[i].peek().Key() < h[j].peek().Key() }
+func (h cursorHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *cursorHeap[T]) Push(x any) { *h = append(*h, x.(*sliceCursor[T])) }
+
+func (h *cursorHeap[T]) Pop() any {
+	old := *h
+	n := len(old)
+	c := old[n-1]
+	*h = old[:n-1]
+	return c
+}
+
+// mergeByKey merges already key-sorted slices into one globally sorted
+// slice using a small min-heap over the current head of each slice.
+func mergeByKey[T Sortable](slices [][]*Element[T]) []*Element[T] {
+	h := make(cursorHeap[T], 0, len(slices))
+	for _, s := range slices {
+		if len(s) > 0 {
+			h = append(h, &sliceCursor[T]{elems: s})
+		}
+	}
+	heap.Init(&h)
+
+	var result []*Element[T]
+	for h.Len() > 0 {
+		c := h[0]
+		result = append(result, c.peek())
+		c.pos++
+		if c.pos == len(c.elems) {
+			heap.Pop(&h)
+		} else {
+			heap.Fix(&h, 0)
+		}
+	}
+	return result
+}
+
+// hashKey maps a Sortable key onto the uint64 hash space: FNV-1a for
+// strings, and an integer finalizer mix for numbers, so that consecutive
+// keys don't pile up on the same shard.
+func hashKey[T Sortable](key T) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return fnv1a(k)
+	default:
+		return mixUint64(numberBits(k))
+	}
+}
+
+// fnv1a is the 64-bit FNV-1a hash, used for string keys so the package
+// stays self-contained rather than pulling in a third-party hash module.
+func fnv1a(s string) uint64 {
+	const (
+		offsetBasis uint64 = 14695981039346656037
+		prime       uint64 = 1099511628211
+	)
+
+	h := offsetBasis
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime
+	}
+	return h
+}
+
+// numberBits reinterprets any of the Number types as a uint64 bit pattern,
+// ready for mixUint64.
+func numberBits(v any) uint64 {
+	switch n := v.(type) {
+	case int:
+		return uint64(n)
+	case int8:
+		return uint64(n)
+	case int16:
+		return uint64(n)
+	case int32:
+		return uint64(n)
+	case int64:
+		return uint64(n)
+	case uint:
+		return uint64(n)
+	case uint8:
+		return uint64(n)
+	case uint16:
+		return uint64(n)
+	case uint32:
+		return uint64(n)
+	case uint64:
+		return n
+	case float32:
+		return uint64(math.Float32bits(n))
+	case float64:
+		return math.Float64bits(n)
+	default:
+		panic(fmt.Sprintf("skiplist: unsupported key type %T", v))
+	}
+}
+
+// mixUint64 is the splitmix64 finalizer, used as a cheap identity-ish hash
+// for integer keys so that nearby keys are spread across shards.
+func mixUint64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,131 @@
+package skiplist
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentSkipListBasic(t *testing.T) {
+	list := NewConcurrentWithShards[int](4)
+
+	for i := 0; i < 100; i++ {
+		list.Set(i, i*i)
+	}
+	if list.Len() != 100 {
+		t.Fatalf("Len() = %d, want 100", list.Len())
+	}
+
+	if e := list.Get(42); e == nil || e.Value() != 42*42 {
+		t.Fatalf("Get(42) = %v, want %d", e, 42*42)
+	}
+
+	if e := list.Remove(42); e == nil {
+		t.Fatalf("Remove(42) = nil, want element")
+	}
+	if e := list.Get(42); e != nil {
+		t.Fatalf("Get(42) after remove = %v, want nil", e)
+	}
+	if list.Len() != 99 {
+		t.Fatalf("Len() after remove = %d, want 99", list.Len())
+	}
+}
+
+func TestConcurrentSkipListGlobalOrder(t *testing.T) {
+	list := NewConcurrentWithShards[int](8)
+
+	for i := 99; i >= 0; i-- {
+		list.Set(i, i)
+	}
+
+	front := list.Front()
+	if front == nil || front.Key() != 0 {
+		t.Fatalf("Front() = %v, want key 0", front)
+	}
+
+	all := list.Iter()
+	if len(all) != 100 {
+		t.Fatalf("Iter() returned %d elements, want 100", len(all))
+	}
+	for i, e := range all {
+		if e.Key() != i {
+			t.Fatalf("Iter()[%d].Key() = %d, want %d", i, e.Key(), i)
+		}
+	}
+
+	ranged := list.Range(10, 19)
+	if len(ranged) != 10 {
+		t.Fatalf("Range(10, 19) returned %d elements, want 10", len(ranged))
+	}
+	for i, e := range ranged {
+		if e.Key() != 10+i {
+			t.Fatalf("Range(10, 19)[%d].Key() = %d, want %d", i, e.Key(), 10+i)
+		}
+	}
+}
+
+func TestConcurrentSkipListParallelWriters(t *testing.T) {
+	list := NewConcurrentWithShards[int](16)
+
+	var wg sync.WaitGroup
+	const perGoroutine = 200
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				key := g*perGoroutine + i
+				list.Set(key, key)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if want := 8 * perGoroutine; list.Len() != want {
+		t.Fatalf("Len() = %d, want %d", list.Len(), want)
+	}
+	for g := 0; g < 8; g++ {
+		for i := 0; i < perGoroutine; i++ {
+			key := g*perGoroutine + i
+			if e := list.Get(key); e == nil || e.Value() != key {
+				t.Fatalf("Get(%d) = %v, want %d", key, e, key)
+			}
+		}
+	}
+}
+
+// BenchmarkConcurrentSkipList_Set compares Set throughput between a single
+// SkipList (one RWMutex for all writers) and a ConcurrentSkipList (writers
+// on different shards don't contend), at increasing goroutine counts. The
+// request asks for near-linear scaling on the sharded version up to
+// GOMAXPROCS; run with -cpu=1,2,4,8 to see it.
+func BenchmarkConcurrentSkipList_Set(b *testing.B) {
+	for _, goroutines := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("SkipList/g=%d", goroutines), func(b *testing.B) {
+			list := New[int]()
+			benchmarkParallelSet(b, goroutines, func(key int) { list.Set(key, key) })
+		})
+		b.Run(fmt.Sprintf("ConcurrentSkipList/g=%d", goroutines), func(b *testing.B) {
+			list := NewConcurrent[int]()
+			benchmarkParallelSet(b, goroutines, func(key int) { list.Set(key, key) })
+		})
+	}
+}
+
+func benchmarkParallelSet(b *testing.B, goroutines int, set func(key int)) {
+	b.ResetTimer()
+
+	var wg sync.WaitGroup
+	keysPerGoroutine := (b.N + goroutines - 1) / goroutines
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			base := g * keysPerGoroutine
+			for i := 0; i < keysPerGoroutine; i++ {
+				set(base + i)
+			}
+		}(g)
+	}
+	wg.Wait()
+}
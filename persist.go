@@ -0,0 +1,516 @@
+package skiplist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"sync/atomic"
+)
+
+// ValueMarshaler encodes an element's value for Snapshot/WAL persistence.
+type ValueMarshaler func(value interface{}) ([]byte, error)
+
+// ValueUnmarshaler decodes a value previously produced by a ValueMarshaler.
+type ValueUnmarshaler func(data []byte) (interface{}, error)
+
+// SetValueCodec overrides how this list encodes element values in Snapshot
+// and in the WAL records it appends after OpenWithWAL has returned. Load and
+// OpenWithWAL build their own new list, so they always decode with the
+// gob-based default; unmarshal is stored but has no effect on them. The
+// default gob encoding only supports the Number types, string, and whatever
+// concrete types the caller has separately gob.Register'd.
+func (list *SkipList[T]) SetValueCodec(marshal ValueMarshaler, unmarshal ValueUnmarshaler) {
+	list.marshalValue = marshal
+	list.unmarshalValue = unmarshal
+}
+
+func init() {
+	for _, zero := range []interface{}{
+		int(0), int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		float32(0), float64(0), "",
+	} {
+		gob.Register(zero)
+	}
+}
+
+func defaultMarshalValue(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func defaultUnmarshalValue(data []byte) (interface{}, error) {
+	var value interface{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Snapshot writes every element, in ascending key order, to w as a compact
+// binary format: a varint count followed by one block per element (a
+// length-prefixed key, a length-prefixed value, and a CRC32 of both), so
+// that Load can detect truncation or corruption. Node heights are not
+// persisted; Load reassigns fresh random heights on read.
+func (list *SkipList[T]) Snapshot(w io.Writer) error {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+
+	marshal := list.marshalValue
+	if marshal == nil {
+		marshal = defaultMarshalValue
+	}
+
+	bw := bufio.NewWriter(w)
+	if err := writeUvarint(bw, uint64(list.length.Load())); err != nil {
+		return err
+	}
+
+	for e := list.loadNext(0); e != nil; e = e.loadNext(0) {
+		keyBytes, err := encodeKey(e.key)
+		if err != nil {
+			return err
+		}
+		valBytes, err := marshal(e.value)
+		if err != nil {
+			return fmt.Errorf("skiplist: marshal value for key %v: %w", e.key, err)
+		}
+		if err := writeBlock(bw, keyBytes, valBytes); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Load rebuilds a skip list from a Snapshot written by (*SkipList[T]).Snapshot,
+// using DefaultMaxLevel. It runs in O(n): nodes are threaded bottom-up with a
+// running prevs[] array instead of being inserted one at a time through Set.
+func Load[T Sortable](r io.Reader) (*SkipList[T], error) {
+	return loadSnapshot[T](r, DefaultMaxLevel)
+}
+
+// loadSnapshot is the shared implementation behind Load and OpenWithWAL,
+// parameterized on maxLevel since OpenWithWAL accepts one explicitly.
+func loadSnapshot[T Sortable](r io.Reader, maxLevel int) (*SkipList[T], error) {
+	list := newSkipList[T](operatorCompare[T], maxLevel)
+
+	br := bufio.NewReader(r)
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("skiplist: read element count: %w", err)
+	}
+
+	unmarshal := list.unmarshalValue
+	if unmarshal == nil {
+		unmarshal = defaultUnmarshalValue
+	}
+
+	// prevs[i] is the last node so far whose forward pointer at level i has
+	// been set; ranks[i] is that node's 1-based position in the bottom
+	// level (0 for the head). Since elements arrive in ascending key order,
+	// each one only ever extends the tail of every level it participates in.
+	prevs := make([]*elementNode[T], list.maxLevel)
+	ranks := make([]int, list.maxLevel)
+	for i := range prevs {
+		prevs[i] = &list.elementNode
+	}
+
+	var prevElem0 *Element[T]
+	for pos := 1; uint64(pos) <= count; pos++ {
+		keyBytes, valBytes, err := readBlock(br)
+		if err != nil {
+			return nil, fmt.Errorf("skiplist: read element %d: %w", pos-1, err)
+		}
+
+		value, err := unmarshal(valBytes)
+		if err != nil {
+			return nil, fmt.Errorf("skiplist: unmarshal value for element %d: %w", pos-1, err)
+		}
+
+		key, err := decodeKey[T](keyBytes)
+		if err != nil {
+			return nil, fmt.Errorf("skiplist: decode key for element %d: %w", pos-1, err)
+		}
+
+		level := list.randLevel()
+		element := &Element[T]{
+			elementNode: elementNode[T]{
+				next:  make([]atomic.Pointer[Element[T]], level),
+				width: make([]int, level),
+			},
+			key:   key,
+			value: value,
+		}
+		element.prev.Store(prevElem0)
+
+		for i := 0; i < level; i++ {
+			prevs[i].width[i] = pos - ranks[i]
+			prevs[i].storeNext(i, element)
+			ranks[i] = pos
+			prevs[i] = &element.elementNode
+		}
+
+		prevElem0 = element
+	}
+
+	list.length.Store(int64(count))
+	list.tail = prevElem0
+	return list, nil
+}
+
+const (
+	walOpSet    byte = 1
+	walOpRemove byte = 2
+)
+
+// OpenWithWAL opens (or creates) a crash-safe skip list backed by a snapshot
+// file at path and a write-ahead log at path+".wal". Every Set/Remove is
+// appended to the WAL and fsync'd before the call returns; on the next
+// OpenWithWAL the snapshot is loaded and the WAL replayed on top of it, so no
+// acknowledged write is lost across a crash. Call (*SkipList[T]).Close when
+// done to release the WAL file handle; it does not rewrite the snapshot.
+func OpenWithWAL[T Sortable](path string, maxLevel int) (*SkipList[T], error) {
+	var list *SkipList[T]
+
+	f, err := os.Open(path)
+	switch {
+	case err == nil:
+		list, err = loadSnapshot[T](f, maxLevel)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("skiplist: load snapshot %s: %w", path, err)
+		}
+	case os.IsNotExist(err):
+		list = newSkipList[T](operatorCompare[T], maxLevel)
+	default:
+		return nil, fmt.Errorf("skiplist: open snapshot %s: %w", path, err)
+	}
+
+	walPath := path + ".wal"
+	if err := list.replayWAL(walPath); err != nil {
+		return nil, fmt.Errorf("skiplist: replay WAL %s: %w", walPath, err)
+	}
+
+	wal, err := os.OpenFile(walPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("skiplist: open WAL %s: %w", walPath, err)
+	}
+	list.wal = wal
+
+	return list, nil
+}
+
+// Close releases the WAL file handle opened by OpenWithWAL. It is a no-op
+// for a list created any other way.
+func (list *SkipList[T]) Close() error {
+	if list.wal == nil {
+		return nil
+	}
+	err := list.wal.Close()
+	list.wal = nil
+	return err
+}
+
+// replayWAL re-applies every record in the WAL at path (if it exists) via
+// the normal Set/Remove path. list.wal is still nil at this point, so these
+// calls don't re-append to the log they're replaying.
+func (list *SkipList[T]) replayWAL(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	unmarshal := list.unmarshalValue
+	if unmarshal == nil {
+		unmarshal = defaultUnmarshalValue
+	}
+
+	br := bufio.NewReader(f)
+	for {
+		op, err := br.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch op {
+		case walOpSet:
+			keyBytes, valBytes, err := readBlock(br)
+			if err != nil {
+				return walReplayErr(err)
+			}
+			value, err := unmarshal(valBytes)
+			if err != nil {
+				return err
+			}
+			key, err := decodeKey[T](keyBytes)
+			if err != nil {
+				return err
+			}
+			list.Set(key, value)
+		case walOpRemove:
+			keyBytes, err := readKeyOnlyBlock(br)
+			if err != nil {
+				return walReplayErr(err)
+			}
+			key, err := decodeKey[T](keyBytes)
+			if err != nil {
+				return err
+			}
+			list.Remove(key)
+		default:
+			return fmt.Errorf("skiplist: unknown WAL op %d", op)
+		}
+	}
+}
+
+// walReplayErr treats a truncated final record - the expected shape of a
+// WAL torn by a crash mid-write - as a clean end of the durable log rather
+// than a replay failure.
+func walReplayErr(err error) error {
+	if err == io.ErrUnexpectedEOF || err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// appendWAL logs one Set/Remove to the WAL and fsyncs it, if a WAL is open.
+// A WAL write failure means the durability OpenWithWAL promises no longer
+// holds, so it panics rather than letting the caller believe the operation
+// was made durable.
+func (list *SkipList[T]) appendWAL(op byte, key T, value interface{}) {
+	if list.wal == nil {
+		return
+	}
+
+	if err := list.writeWALRecord(op, key, value); err != nil {
+		panic(fmt.Sprintf("skiplist: WAL write failed, durability lost: %v", err))
+	}
+}
+
+func (list *SkipList[T]) writeWALRecord(op byte, key T, value interface{}) error {
+	bw := bufio.NewWriter(list.wal)
+	if err := bw.WriteByte(op); err != nil {
+		return err
+	}
+
+	keyBytes, err := encodeKey(key)
+	if err != nil {
+		return err
+	}
+	switch op {
+	case walOpSet:
+		marshal := list.marshalValue
+		if marshal == nil {
+			marshal = defaultMarshalValue
+		}
+		var valBytes []byte
+		if valBytes, err = marshal(value); err != nil {
+			return fmt.Errorf("marshal value: %w", err)
+		}
+		err = writeBlock(bw, keyBytes, valBytes)
+	case walOpRemove:
+		err = writeKeyOnlyBlock(bw, keyBytes)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+
+	return list.wal.Sync()
+}
+
+// writeUvarint writes x as an unsigned varint.
+func writeUvarint(w *bufio.Writer, x uint64) error {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], x)
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+// writeBlock writes a length-prefixed key, a length-prefixed value, and a
+// CRC32 of both.
+func writeBlock(w *bufio.Writer, keyBytes, valBytes []byte) error {
+	if err := writeUvarint(w, uint64(len(keyBytes))); err != nil {
+		return err
+	}
+	if _, err := w.Write(keyBytes); err != nil {
+		return err
+	}
+	if err := writeUvarint(w, uint64(len(valBytes))); err != nil {
+		return err
+	}
+	if _, err := w.Write(valBytes); err != nil {
+		return err
+	}
+
+	h := crc32.NewIEEE()
+	h.Write(keyBytes)
+	h.Write(valBytes)
+	return writeCRC(w, h.Sum32())
+}
+
+// writeKeyOnlyBlock writes a length-prefixed key and a CRC32 of it, for WAL
+// remove records that carry no value.
+func writeKeyOnlyBlock(w *bufio.Writer, keyBytes []byte) error {
+	if err := writeUvarint(w, uint64(len(keyBytes))); err != nil {
+		return err
+	}
+	if _, err := w.Write(keyBytes); err != nil {
+		return err
+	}
+	return writeCRC(w, crc32.ChecksumIEEE(keyBytes))
+}
+
+func writeCRC(w *bufio.Writer, crc uint32) error {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], crc)
+	_, err := w.Write(buf[:])
+	return err
+}
+
+// readBlock reads back a block written by writeBlock, verifying its CRC32.
+func readBlock(r *bufio.Reader) (keyBytes, valBytes []byte, err error) {
+	if keyBytes, err = readLenPrefixed(r); err != nil {
+		return nil, nil, err
+	}
+	if valBytes, err = readLenPrefixed(r); err != nil {
+		return nil, nil, err
+	}
+
+	crc, err := readCRC(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h := crc32.NewIEEE()
+	h.Write(keyBytes)
+	h.Write(valBytes)
+	if h.Sum32() != crc {
+		return nil, nil, fmt.Errorf("skiplist: checksum mismatch while reading block")
+	}
+
+	return keyBytes, valBytes, nil
+}
+
+// readKeyOnlyBlock reads back a block written by writeKeyOnlyBlock.
+func readKeyOnlyBlock(r *bufio.Reader) ([]byte, error) {
+	keyBytes, err := readLenPrefixed(r)
+	if err != nil {
+		return nil, err
+	}
+
+	crc, err := readCRC(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if crc32.ChecksumIEEE(keyBytes) != crc {
+		return nil, fmt.Errorf("skiplist: checksum mismatch while reading key")
+	}
+
+	return keyBytes, nil
+}
+
+func readLenPrefixed(r *bufio.Reader) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+func readCRC(r *bufio.Reader) (uint32, error) {
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(buf[:]), nil
+}
+
+// encodeKey renders a key as bytes: the UTF-8 bytes for a string, or the
+// big-endian bit pattern of its value for any Number type. Snapshot/Load are
+// methods/functions over a plain SkipList[T], which (since NewWithComparator
+// lets T be any) isn't statically known to be Sortable, so this dispatches
+// on the key's dynamic type instead and errors out for anything else.
+func encodeKey[T any](key T) ([]byte, error) {
+	switch k := any(key).(type) {
+	case string:
+		return []byte(k), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		buf := make([]byte, 8)
+		binary.BigEndian.PutUint64(buf, numberBits(k))
+		return buf, nil
+	default:
+		return nil, fmt.Errorf("skiplist: key type %T is not natively encodable for persistence", key)
+	}
+}
+
+// decodeKey reverses encodeKey, dispatching on T's own zero value.
+func decodeKey[T any](data []byte) (T, error) {
+	var zero T
+	switch any(zero).(type) {
+	case string:
+		return any(string(data)).(T), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return any(numberFromBits(zero, binary.BigEndian.Uint64(data))).(T), nil
+	default:
+		return zero, fmt.Errorf("skiplist: key type %T is not natively decodable for persistence", zero)
+	}
+}
+
+// numberFromBits reinterprets bits as the same concrete numeric type as
+// zero, the counterpart to numberBits.
+func numberFromBits(zero any, bits uint64) any {
+	switch zero.(type) {
+	case int:
+		return int(int64(bits))
+	case int8:
+		return int8(int64(bits))
+	case int16:
+		return int16(int64(bits))
+	case int32:
+		return int32(int64(bits))
+	case int64:
+		return int64(bits)
+	case uint:
+		return uint(bits)
+	case uint8:
+		return uint8(bits)
+	case uint16:
+		return uint16(bits)
+	case uint32:
+		return uint32(bits)
+	case uint64:
+		return bits
+	case float32:
+		return math.Float32frombits(uint32(bits))
+	case float64:
+		return math.Float64frombits(bits)
+	default:
+		panic(fmt.Sprintf("skiplist: unsupported key type %T", zero))
+	}
+}
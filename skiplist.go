@@ -1,9 +1,9 @@
 package skiplist
 
 import (
-	"fmt"
 	"math"
 	"math/rand"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,18 +16,23 @@ const (
 	DefaultProbability float64 = 1 / math.E
 )
 
-// Len returns the number of elements in the list.
+// Len returns the number of elements in the list. Lock-free: safe to call
+// while other goroutines call Set/Remove.
 func (list *SkipList[T]) Len() int {
-	list.mutex.RLock()
-	defer list.mutex.RUnlock()
-	return list.length
+	return int(list.length.Load())
 }
 
-// Front returns the head node of the list.
+// Front returns the head node of the list. Lock-free: safe to call while
+// other goroutines call Set/Remove.
 func (list *SkipList[T]) Front() *Element[T] {
+	return list.loadNext(0)
+}
+
+// Back returns the last node of the list, or nil if the list is empty.
+func (list *SkipList[T]) Back() *Element[T] {
 	list.mutex.RLock()
 	defer list.mutex.RUnlock()
-	return list.next[0]
+	return list.tail
 }
 
 // Set inserts a value in the list with the specified key, ordered by the key.
@@ -39,78 +44,269 @@ func (list *SkipList[T]) Set(key T, value interface{}) *Element[T] {
 	defer list.mutex.Unlock()
 
 	var element *Element[T]
-	prevs := list.getPrevElementNodes(key)
+	prevs, ranks, prevElem0 := list.getPrevElementNodes(key)
 
 	// if key == second element, than update and return the second element
-	if element = prevs[0].next[0]; element != nil && element.key <= key {
-		if element.key < key {
-			fmt.Println(element.key)
-		}
-
+	if element = prevs[0].loadNext(0); element != nil && list.cmp(element.key, key) <= 0 {
 		element.value = value
+		list.appendWAL(walOpSet, key, value)
 		return element
 	}
 
 	// make new node and generate the random level,
 	// this node will appears from level 0 to the random level.
+	level := list.randLevel()
 	element = &Element[T]{
 		elementNode: elementNode[T]{
-			next: make([]*Element[T], list.randLevel()),
+			next:  make([]atomic.Pointer[Element[T]], level),
+			width: make([]int, level),
 		},
 		key:   key,
 		value: value,
 	}
 
-	// insert new node into skiplist.
-	for i := range element.next {
-		element.next[i] = prevs[i].next[i]
-		prevs[i].next[i] = element
+	// finish building the new node's own forward pointers and widths
+	// before it becomes reachable from anywhere: a lock-free reader must
+	// never find element through a prevs[i] pointer while element.next is
+	// still only partially populated.
+	for i := 0; i < level; i++ {
+		element.storeNext(i, prevs[i].loadNext(i))
+		element.width[i] = prevs[i].width[i] - (ranks[0] - ranks[i])
+	}
+
+	// now publish element into the index, splitting each crossed forward
+	// pointer's span between it and what remains ahead of it. Top level
+	// first, level 0 last, so a reader that finds element at a high level
+	// can already rely on every lower level it descends through.
+	for i := level - 1; i >= 0; i-- {
+		prevs[i].storeNext(i, element)
+		prevs[i].width[i] = (ranks[0] - ranks[i]) + 1
 	}
 
-	list.length++
+	// levels above the new node's height simply grow by one element.
+	for i := level; i < list.maxLevel; i++ {
+		prevs[i].width[i]++
+	}
+
+	// fix up the level-0 back pointers around the new node.
+	element.prev.Store(prevElem0)
+	if next0 := element.loadNext(0); next0 != nil {
+		next0.prev.Store(element)
+	} else {
+		list.tail = element
+	}
+
+	list.length.Add(1)
+	list.appendWAL(walOpSet, key, value)
 	return element
 }
 
 // Get finds an element by key. It returns element pointer if found, nil if not found.
-// Locking is optimistic and happens only after searching with a fast check for deletion after locking.
+// Lock-free: safe to call while other goroutines call Set/Remove.
 func (list *SkipList[T]) Get(key T) *Element[T] {
+	var prev *elementNode[T] = &list.elementNode
+	var next *Element[T]
+
+	for i := list.maxLevel - 1; i >= 0; i-- {
+		next = prev.loadNext(i)
+
+		for next != nil && list.cmp(key, next.key) > 0 {
+			prev = &next.elementNode
+			next = next.loadNext(i)
+		}
+	}
+
+	if next != nil && list.cmp(next.key, key) == 0 {
+		return next
+	}
+
+	return nil
+}
+
+// Range returns, in key order, every element whose key falls within [lo, hi].
+// Returns nil if lo > hi or no element falls in range. Lock-free: safe to
+// call while other goroutines call Set/Remove.
+func (list *SkipList[T]) Range(lo, hi T) []*Element[T] {
+	if list.cmp(lo, hi) > 0 {
+		return nil
+	}
+
+	var prev *elementNode[T] = &list.elementNode
+	var next *Element[T]
+
+	for i := list.maxLevel - 1; i >= 0; i-- {
+		next = prev.loadNext(i)
+
+		for next != nil && list.cmp(next.key, lo) < 0 {
+			prev = &next.elementNode
+			next = next.loadNext(i)
+		}
+	}
+
+	var result []*Element[T]
+	for e := next; e != nil && list.cmp(e.key, hi) <= 0; e = e.loadNext(0) {
+		result = append(result, e)
+	}
+
+	return result
+}
+
+// RangeReverse returns, in descending key order, every element whose key
+// falls within [lo, hi]. Returns nil if lo > hi or no element falls in range.
+func (list *SkipList[T]) RangeReverse(hi, lo T) []*Element[T] {
 	list.mutex.RLock()
 	defer list.mutex.RUnlock()
 
+	if list.cmp(lo, hi) > 0 {
+		return nil
+	}
+
 	var prev *elementNode[T] = &list.elementNode
-	var next *Element[T]
+	var cur, next *Element[T]
 
 	for i := list.maxLevel - 1; i >= 0; i-- {
-		next = prev.next[i]
+		next = prev.loadNext(i)
 
-		for next != nil && key > next.key {
+		for next != nil && list.cmp(next.key, hi) <= 0 {
 			prev = &next.elementNode
-			next = next.next[i]
+			cur = next
+			next = next.loadNext(i)
 		}
 	}
 
-	if next != nil && next.key == key {
-		return next
+	var result []*Element[T]
+	for e := cur; e != nil && list.cmp(e.key, lo) >= 0; e = e.prev.Load() {
+		result = append(result, e)
+	}
+
+	return result
+}
+
+// Rank returns the 1-based rank of the element with the given key, i.e. how
+// many elements (including itself) are <= key. Returns 0 if key is not found.
+func (list *SkipList[T]) Rank(key T) int {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+
+	var prev *elementNode[T] = &list.elementNode
+	var cur, next *Element[T]
+	rank := 0
+
+	for i := list.maxLevel - 1; i >= 0; i-- {
+		next = prev.loadNext(i)
+
+		for next != nil && list.cmp(next.key, key) <= 0 {
+			rank += prev.width[i]
+			prev = &next.elementNode
+			cur = next
+			next = next.loadNext(i)
+		}
+	}
+
+	if cur != nil && list.cmp(cur.key, key) == 0 {
+		return rank
+	}
+
+	return 0
+}
+
+// Select returns the element at the given 1-based rank, or nil if rank is
+// out of range. Select(1) is equivalent to Front().
+func (list *SkipList[T]) Select(rank int) *Element[T] {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+
+	if rank <= 0 {
+		return nil
+	}
+
+	var prev *elementNode[T] = &list.elementNode
+	var cur *Element[T]
+	traversed := 0
+
+	for i := list.maxLevel - 1; i >= 0; i-- {
+		for next := prev.loadNext(i); next != nil && traversed+prev.width[i] <= rank; next = prev.loadNext(i) {
+			traversed += prev.width[i]
+			cur = next
+			prev = &cur.elementNode
+		}
+	}
+
+	if traversed == rank {
+		return cur
 	}
 
 	return nil
 }
 
+// RangeByRank returns, in key order, the elements whose 1-based ranks fall
+// within [startRank, endRank]. Returns nil if the range is empty or invalid.
+func (list *SkipList[T]) RangeByRank(startRank, endRank int) []*Element[T] {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+
+	if startRank <= 0 || endRank < startRank {
+		return nil
+	}
+
+	var prev *elementNode[T] = &list.elementNode
+	var cur *Element[T]
+	traversed := 0
+
+	for i := list.maxLevel - 1; i >= 0; i-- {
+		for next := prev.loadNext(i); next != nil && traversed+prev.width[i] <= startRank; next = prev.loadNext(i) {
+			traversed += prev.width[i]
+			cur = next
+			prev = &cur.elementNode
+		}
+	}
+
+	if cur == nil || traversed != startRank {
+		return nil
+	}
+
+	var result []*Element[T]
+	for e, rank := cur, startRank; e != nil && rank <= endRank; e, rank = e.loadNext(0), rank+1 {
+		result = append(result, e)
+	}
+
+	return result
+}
+
 // Remove deletes an element from the list.
 // Returns removed element pointer if found, nil if not found.
 // Locking is optimistic and happens only after searching with a fast check on adjacent nodes after locking.
 func (list *SkipList[T]) Remove(key T) *Element[T] {
 	list.mutex.Lock()
 	defer list.mutex.Unlock()
-	prevs := list.getPrevElementNodes(key)
+	prevs, _, prevElem0 := list.getPrevElementNodes(key)
 
 	// found the element, remove it
-	if element := prevs[0].next[0]; element != nil && element.key <= key {
-		for k, v := range element.next {
-			prevs[k].next[k] = v
+	if element := prevs[0].loadNext(0); element != nil && list.cmp(element.key, key) <= 0 {
+		// unlink top level first, level 0 last: element stays reachable at
+		// level 0 until the final store, so a lock-free reader finds it
+		// until then and not after, and it is never reachable at a higher
+		// level once it is gone from level 0. element's own next pointers
+		// are left intact for a reader already positioned on it.
+		for k := len(element.next) - 1; k >= 0; k-- {
+			prevs[k].storeNext(k, element.loadNext(k))
+			prevs[k].width[k] += element.width[k] - 1
+		}
+
+		// levels above the removed node's height just lose one element.
+		for k := len(element.next); k < list.maxLevel; k++ {
+			prevs[k].width[k]--
+		}
+
+		// fix up the level-0 back pointers around the removed node.
+		if next0 := element.loadNext(0); next0 != nil {
+			next0.prev.Store(prevElem0)
+		} else {
+			list.tail = prevElem0
 		}
 
-		list.length--
+		list.length.Add(-1)
+		list.appendWAL(walOpRemove, key, nil)
 		return element
 	}
 
@@ -122,31 +318,45 @@ func (list *SkipList[T]) Remove(key T) *Element[T] {
 // caches them. This approach is similar to a "search finger" as described by Pugh:
 // http://citeseerx.ist.psu.edu/viewdoc/summary?doi=10.1.1.17.524
 //
+// It also returns, for each level, the rank (number of level-0 nodes from the
+// head) of the chosen previous node, plus the level-0 previous *Element (nil
+// if the search never left the head), so that callers can derive span/width
+// and fix up back-pointers without a second traversal.
+//
 // 从上到下，从左到右搜索跳表索引，返回每一层的命中结点。
 // 下标就是层数，[0] 是最底层，[maxLevel - 1] 是最顶层。
-func (list *SkipList[T]) getPrevElementNodes(key T) []*elementNode[T] {
+func (list *SkipList[T]) getPrevElementNodes(key T) ([]*elementNode[T], []int, *Element[T]) {
 	var prev *elementNode[T] = &list.elementNode
-	var next *Element[T]
+	var next, prevElem0 *Element[T]
 
 	prevs := list.prevNodesCache
+	ranks := list.rankCache
 
 	// 从最上层开始找
 	for i := list.maxLevel - 1; i >= 0; i-- {
+		if i == list.maxLevel-1 {
+			ranks[i] = 0
+		} else {
+			ranks[i] = ranks[i+1]
+		}
+
 		// next 是当前层的下一个节点
-		next = prev.next[i]
+		next = prev.loadNext(i)
 
 		// 水平遍历，直到到达尾部，或者 key > next，
 		// 说明当前层就选择当前的节点（prev）
-		for next != nil && key > next.key {
+		for next != nil && list.cmp(key, next.key) > 0 {
+			ranks[i] += prev.width[i]
 			prev = &next.elementNode
-			next = next.next[i]
+			prevElem0 = next
+			next = next.loadNext(i)
 		}
 
 		// 将每一层所选择的节点存入 prevs 中
 		prevs[i] = prev
 	}
 
-	return prevs
+	return prevs, ranks, prevElem0
 }
 
 // SetProbability changes the current P value of the list.
@@ -187,21 +397,58 @@ func probabilityTable(probability float64, MaxLevel int) (table []float64) {
 // number of elements in a skip list). See http://citeseerx.ist.psu.edu/viewdoc/summary?doi=10.1.1.17.524
 // Returns a pointer to the new list.
 func NewWithMaxLevel[T Sortable](maxLevel int) *SkipList[T] {
+	return newSkipList[T](operatorCompare[T], maxLevel)
+}
+
+// New creates a new skip list with default parameters. Returns a pointer to the new list.
+func New[T Sortable]() *SkipList[T] {
+	return NewWithMaxLevel[T](DefaultMaxLevel)
+}
+
+// NewWithComparator creates a new skip list ordered by cmp instead of the
+// built-in </==/> operators, so keys that don't satisfy Sortable (structs,
+// tuples, time.Time, big.Int, case-insensitive strings, ...) can still be
+// stored. cmp must return a negative number if a < b, zero if a == b, and a
+// positive number if a > b, consistently with the total order it imposes.
+func NewWithComparator[T any](cmp func(a, b T) int, maxLevel int) *SkipList[T] {
+	if cmp == nil {
+		panic("cmp for a SkipList must not be nil")
+	}
+
+	return newSkipList[T](cmp, maxLevel)
+}
+
+// newSkipList builds an empty skip list bounded to maxLevel, ordered by cmp.
+func newSkipList[T any](cmp func(a, b T) int, maxLevel int) *SkipList[T] {
 	if maxLevel < 1 || maxLevel > 64 {
 		panic("maxLevel for a SkipList must be a positive integer <= 64")
 	}
 
 	return &SkipList[T]{
-		elementNode:    elementNode[T]{next: make([]*Element[T], maxLevel)},
+		elementNode: elementNode[T]{
+			next:  make([]atomic.Pointer[Element[T]], maxLevel),
+			width: make([]int, maxLevel),
+		},
 		prevNodesCache: make([]*elementNode[T], maxLevel),
+		rankCache:      make([]int, maxLevel),
 		maxLevel:       maxLevel,
 		randSource:     rand.New(rand.NewSource(time.Now().UnixNano())),
 		probability:    DefaultProbability,
 		probTable:      probabilityTable(DefaultProbability, maxLevel),
+		cmp:            cmp,
 	}
 }
 
-// New creates a new skip list with default parameters. Returns a pointer to the new list.
-func New[T Sortable]() *SkipList[T] {
-	return NewWithMaxLevel[T](DefaultMaxLevel)
+// operatorCompare is the default comparator installed by the
+// Sortable-constrained constructors: it orders keys with the built-in
+// </==/> operators.
+func operatorCompare[T Sortable](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
 }
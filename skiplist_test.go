@@ -0,0 +1,196 @@
+package skiplist
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetGetRemove(t *testing.T) {
+	list := New[int]()
+
+	list.Set(3, "c")
+	list.Set(1, "a")
+	list.Set(2, "b")
+
+	if e := list.Get(2); e == nil || e.Value() != "b" {
+		t.Fatalf("Get(2) = %v, want b", e)
+	}
+
+	list.Set(2, "b2")
+	if e := list.Get(2); e == nil || e.Value() != "b2" {
+		t.Fatalf("Get(2) after update = %v, want b2", e)
+	}
+
+	if e := list.Remove(2); e == nil || e.Value() != "b2" {
+		t.Fatalf("Remove(2) = %v, want b2", e)
+	}
+	if e := list.Get(2); e != nil {
+		t.Fatalf("Get(2) after remove = %v, want nil", e)
+	}
+	if list.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", list.Len())
+	}
+}
+
+func TestRange(t *testing.T) {
+	list := New[int]()
+	for _, k := range []int{5, 1, 3, 2, 4} {
+		list.Set(k, k)
+	}
+
+	got := list.Range(2, 4)
+	want := []int{2, 3, 4}
+	if len(got) != len(want) {
+		t.Fatalf("Range(2, 4) = %v, want keys %v", got, want)
+	}
+	for i, e := range got {
+		if e.Key() != want[i] {
+			t.Fatalf("Range(2, 4)[%d] = %d, want %d", i, e.Key(), want[i])
+		}
+	}
+
+	if got := list.Range(4, 2); got != nil {
+		t.Fatalf("Range(4, 2) = %v, want nil", got)
+	}
+	if got := list.Range(10, 20); got != nil {
+		t.Fatalf("Range(10, 20) = %v, want nil", got)
+	}
+}
+
+func TestRangeReverse(t *testing.T) {
+	list := New[int]()
+	for _, k := range []int{5, 1, 3, 2, 4} {
+		list.Set(k, k)
+	}
+
+	got := list.RangeReverse(4, 2)
+	want := []int{4, 3, 2}
+	if len(got) != len(want) {
+		t.Fatalf("RangeReverse(4, 2) = %v, want keys %v", got, want)
+	}
+	for i, e := range got {
+		if e.Key() != want[i] {
+			t.Fatalf("RangeReverse(4, 2)[%d] = %d, want %d", i, e.Key(), want[i])
+		}
+	}
+}
+
+func TestBackAndPrev(t *testing.T) {
+	list := New[int]()
+	for _, k := range []int{1, 2, 3} {
+		list.Set(k, k)
+	}
+
+	back := list.Back()
+	if back == nil || back.Key() != 3 {
+		t.Fatalf("Back() = %v, want key 3", back)
+	}
+
+	var keys []int
+	for e := back; e != nil; e = e.Prev() {
+		keys = append(keys, e.Key())
+	}
+	want := []int{3, 2, 1}
+	if len(keys) != len(want) {
+		t.Fatalf("walked keys = %v, want %v", keys, want)
+	}
+	for i, k := range keys {
+		if k != want[i] {
+			t.Fatalf("walked keys = %v, want %v", keys, want)
+		}
+	}
+
+	list.Remove(2)
+	if back := list.Back(); back == nil || back.Prev() == nil || back.Prev().Key() != 1 {
+		t.Fatalf("Back().Prev() after removing middle element = %v, want key 1", back.Prev())
+	}
+}
+
+func TestRankAndSelect(t *testing.T) {
+	list := New[int]()
+	for _, k := range []int{10, 20, 30, 40, 50} {
+		list.Set(k, k)
+	}
+
+	if r := list.Rank(30); r != 3 {
+		t.Fatalf("Rank(30) = %d, want 3", r)
+	}
+	if r := list.Rank(99); r != 0 {
+		t.Fatalf("Rank(99) = %d, want 0", r)
+	}
+
+	if e := list.Select(1); e == nil || e.Key() != 10 {
+		t.Fatalf("Select(1) = %v, want key 10", e)
+	}
+	if e := list.Select(5); e == nil || e.Key() != 50 {
+		t.Fatalf("Select(5) = %v, want key 50", e)
+	}
+	if e := list.Select(6); e != nil {
+		t.Fatalf("Select(6) = %v, want nil", e)
+	}
+	if e := list.Select(0); e != nil {
+		t.Fatalf("Select(0) = %v, want nil", e)
+	}
+}
+
+func TestRangeByRank(t *testing.T) {
+	list := New[int]()
+	for _, k := range []int{10, 20, 30} {
+		list.Set(k, k)
+	}
+
+	got := list.RangeByRank(2, 3)
+	want := []int{20, 30}
+	if len(got) != len(want) {
+		t.Fatalf("RangeByRank(2, 3) = %v, want keys %v", got, want)
+	}
+	for i, e := range got {
+		if e.Key() != want[i] {
+			t.Fatalf("RangeByRank(2, 3)[%d] = %d, want %d", i, e.Key(), want[i])
+		}
+	}
+
+	if got := list.RangeByRank(0, 2); got != nil {
+		t.Fatalf("RangeByRank(0, 2) = %v, want nil", got)
+	}
+	if got := list.RangeByRank(5, 10); got != nil {
+		t.Fatalf("RangeByRank(5, 10) out of range = %v, want nil", got)
+	}
+	if got := list.RangeByRank(2, 1); got != nil {
+		t.Fatalf("RangeByRank(2, 1) = %v, want nil", got)
+	}
+}
+
+func TestNewWithComparator(t *testing.T) {
+	// Case-insensitive string ordering, a key shape Sortable can't express.
+	list := NewWithComparator[string](func(a, b string) int {
+		a, b = strings.ToLower(a), strings.ToLower(b)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		default:
+			return 0
+		}
+	}, DefaultMaxLevel)
+
+	list.Set("Banana", 1)
+	list.Set("apple", 2)
+	list.Set("Cherry", 3)
+
+	got := list.Range("apple", "Cherry")
+	want := []string{"apple", "Banana", "Cherry"}
+	if len(got) != len(want) {
+		t.Fatalf("Range = %v, want keys %v", got, want)
+	}
+	for i, e := range got {
+		if e.Key() != want[i] {
+			t.Fatalf("Range[%d] = %s, want %s", i, e.Key(), want[i])
+		}
+	}
+
+	if e := list.Get("APPLE"); e == nil || e.Value() != 2 {
+		t.Fatalf("Get(APPLE) = %v, want 2", e)
+	}
+}
@@ -2,7 +2,9 @@ package skiplist
 
 import (
 	"math/rand"
+	"os"
 	"sync"
+	"sync/atomic"
 )
 
 // Number is a number type
@@ -17,19 +19,46 @@ type Sortable interface {
 	Number | string
 }
 
-type elementNode[T Sortable] struct {
-	// key forward pointers of one node
+type elementNode[T any] struct {
+	// key forward pointers of one node, one atomic.Pointer per level.
 	//
 	// length means this node's height.
 	// if node has forward pointers in level 0~3,
 	// then length is 4.
-	next []*Element[T]
+	//
+	// next is read through loadNext and written through storeNext rather
+	// than indexed directly, so that Get, Front, Next and Range can walk
+	// the list without taking list.mutex: Set publishes a new node's
+	// pointers only after the node's own next slice is fully built, and
+	// Remove unlinks top-down, so a lock-free reader either sees a node
+	// whose next slice is complete or doesn't see it at all.
+	next []atomic.Pointer[Element[T]]
+	// width holds, for each forward pointer in next, the number of
+	// level-0 nodes it skips over (its "span"). width[i] is always
+	// in sync with next[i] and is updated by Set/Remove so that
+	// Rank/Select can walk the index instead of scanning level 0.
+	width []int
+}
+
+// loadNext atomically reads the forward pointer at level i.
+func (n *elementNode[T]) loadNext(i int) *Element[T] {
+	return n.next[i].Load()
+}
+
+// storeNext atomically publishes the forward pointer at level i.
+func (n *elementNode[T]) storeNext(i int, e *Element[T]) {
+	n.next[i].Store(e)
 }
 
-type Element[T Sortable] struct {
+type Element[T any] struct {
 	elementNode[T]
 	key   T
 	value interface{}
+	// prev is the backward pointer at level 0 only, kept in sync by Set
+	// and Remove so the list can be walked backward without re-searching
+	// from the head. It's an atomic.Pointer, like next, so Prev() can walk
+	// backward lock-free alongside concurrent Set/Remove.
+	prev atomic.Pointer[Element[T]]
 }
 
 // Key allows retrieval of the key for a given Element
@@ -44,16 +73,30 @@ func (e *Element[T]) Value() interface{} {
 
 // Next returns the following Element or nil if we're at the end of the list.
 // Only operates on the bottom level of the skip list (a fully linked list).
+// Lock-free: safe to call while other goroutines call Set/Remove.
 func (e *Element[T]) Next() *Element[T] {
-	return e.next[0]
+	return e.loadNext(0)
+}
+
+// Prev returns the preceding Element or nil if we're at the front of the list.
+// Only operates on the bottom level of the skip list (a fully linked list).
+// Lock-free: safe to call while other goroutines call Set/Remove.
+func (e *Element[T]) Prev() *Element[T] {
+	return e.prev.Load()
 }
 
-type SkipList[T Sortable] struct {
+type SkipList[T any] struct {
 	// elementNode forward pointers
 	elementNode[T]
 	// maxLevel 最大高度
-	maxLevel   int
-	Length     int
+	maxLevel int
+	// length is atomic so Len() can read it without taking list.mutex.
+	length atomic.Int64
+	// cmp orders keys: negative if a < b, zero if equal, positive if a > b.
+	// Sortable-constrained constructors (New, NewWithMaxLevel) install an
+	// operator-based comparator; NewWithComparator lets callers supply
+	// their own for keys that aren't comparable with <, ==, >.
+	cmp        func(a, b T) int
 	randSource rand.Source
 	// probability 节点上升的概率
 	probability float64
@@ -66,4 +109,19 @@ type SkipList[T Sortable] struct {
 	//
 	// 从最后一个元素开始，依次向前。
 	prevNodesCache []*elementNode[T]
+	// rankCache parallels prevNodesCache: rankCache[i] is the number of
+	// level-0 nodes between the head and prevNodesCache[i], used by Set
+	// and Remove to keep width up to date.
+	rankCache []int
+	// tail is the last element at level 0, or nil if the list is empty.
+	tail *Element[T]
+	// marshalValue/unmarshalValue override how Snapshot and WAL appends
+	// encode element values. Nil means "use the gob-based default" (see
+	// SetValueCodec); Load and OpenWithWAL decode into a new list whose
+	// codec is always nil.
+	marshalValue   ValueMarshaler
+	unmarshalValue ValueUnmarshaler
+	// wal is the write-ahead log file opened by OpenWithWAL, or nil for a
+	// list created any other way. Set/Remove append to it when non-nil.
+	wal *os.File
 }